@@ -2,8 +2,14 @@
 package jwt
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"math/big"
 	"net/http"
 	"strings"
 	"time"
@@ -13,21 +19,87 @@ import (
 )
 
 // JWTMiddleware provides a Json-Web-Token authentication implementation. On failure, a 401 HTTP response
-// is returned. On success, the wrapped middleware is called, and the userId is made available as
-// r.Env["REMOTE_USER"].(string).
+// is returned. On success, the wrapped middleware is called, and the authenticated identity is made
+// available as r.Env["REMOTE_USER"] (see IdentityHandler and ExtractIdentity).
 // Users can get a token by posting a json request to LoginHandler. The token then needs to be passed in
 // the Authentication header. Example: Authorization:Bearer XXX_TOKEN_XXX
 type JWTMiddleware struct {
 	// Realm name to display to the user. Required.
 	Realm string
 
-	// signing algorithm - possible values are HS256, HS384, HS512
+	// Signing algorithm - possible values are HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384, ES512.
 	// Optional, default is HS256.
 	SigningAlgorithm string
 
-	// Secret key used for signing. Required.
+	// Secret key used for HMAC signing (HS256/HS384/HS512). Required when SigningAlgorithm is an HMAC
+	// algorithm.
 	Key []byte
 
+	// Path to a PEM encoded private key file. Used together with PubKeyFile when SigningAlgorithm is an
+	// RS* or ES* algorithm. If set, the key is parsed and loaded into PrivKey automatically.
+	PrivKeyFile string
+
+	// Path to a PEM encoded public key file. Used together with PrivKeyFile when SigningAlgorithm is an
+	// RS* or ES* algorithm. If set, the key is parsed and loaded into PubKey automatically.
+	PubKeyFile string
+
+	// Parsed private key used for signing tokens when SigningAlgorithm is an RS* or ES* algorithm.
+	// Populated from PrivKeyFile if that is set; otherwise it can be assigned directly, e.g. a
+	// *rsa.PrivateKey or *ecdsa.PrivateKey.
+	PrivKey interface{}
+
+	// Parsed public key used for verifying tokens when SigningAlgorithm is an RS* or ES* algorithm.
+	// Populated from PubKeyFile if that is set; otherwise it can be assigned directly, e.g. a
+	// *rsa.PublicKey or *ecdsa.PublicKey. Also used to serve JWKSHandler.
+	PubKey interface{}
+
+	// Store consulted to check and record revoked tokens, e.g. on logout. If nil, revocation is not
+	// enforced and tokens remain valid until they expire naturally. See TokenStore.
+	TokenStore TokenStore
+
+	// Algorithm names (as returned by jwt.SigningMethod.Alg(), e.g. "HS256") that must never be
+	// accepted even if a token claims to use them. Optional.
+	DisabledAlgorithms []string
+
+	// TokenLookup configures where to look for the token, as a comma separated list of
+	// "source:name" pairs tried in order until one yields a token, e.g.
+	// "header:Authorization,cookie:jwt,query:token". Optional, defaults to "header:Authorization".
+	TokenLookup string
+
+	// Scheme prefixed to the token value in a header source, e.g. "Bearer" or "JWT". An empty
+	// value means the header holds the raw token with no scheme. Optional, defaults to "Bearer".
+	TokenHeadName string
+
+	// Name of the cookie read by a "cookie:" TokenLookup source, and written by
+	// LoginHandler/RefreshHandler when SendCookie is true. Optional, defaults to "jwt".
+	CookieName string
+
+	// Domain attribute of the cookie set by LoginHandler/RefreshHandler. Optional.
+	CookieDomain string
+
+	// Path attribute of the cookie set by LoginHandler/RefreshHandler. Optional, defaults to "/" so
+	// the cookie is sent on every path rather than just the login/refresh endpoint's own path (the
+	// browser default per RFC 6265).
+	CookiePath string
+
+	// SendCookie makes LoginHandler/RefreshHandler additionally set the token as a cookie, for
+	// browser SPA flows where reading/writing the Authorization header is awkward. Optional,
+	// defaults to false.
+	SendCookie bool
+
+	// Secure attribute of the cookie set by LoginHandler/RefreshHandler. Optional.
+	SecureCookie bool
+
+	// HttpOnly attribute of the cookie set by LoginHandler/RefreshHandler. Optional.
+	CookieHTTPOnly bool
+
+	// SameSite attribute of the cookie set by LoginHandler/RefreshHandler. Optional; when left at
+	// its zero value no SameSite attribute is sent.
+	CookieSameSite http.SameSite
+
+	// extractors is built from TokenLookup/TokenHeadName by MiddlewareFunc.
+	extractors []tokenExtractor
+
 	// Duration that a jwt token is valid. Optional, defaults to one hour.
 	Timeout time.Duration
 
@@ -37,22 +109,73 @@ type JWTMiddleware struct {
 	// Optional, defaults to 0 meaning not refreshable.
 	MaxRefresh time.Duration
 
-	// Callback function that should perform the authentication of the user based on userId and
-	// password. Must return true on success, false on failure. Required.
-	Authenticator func(userId string, password string) bool
+	// iss claim set on every issued token and required (if set) on every token a request presents.
+	// Optional, no issuer check is performed when empty.
+	Issuer string
 
-	// Callback function that should perform the authorization of the authenticated user. Called
-	// only after an authentication success. Must return true on success, false on failure.
-	// Optional, default to success.
-	Authorizator func(userId string, r *rest.Request) bool
+	// aud claim set on every issued token. A presented token must carry at least one of these
+	// values in its own aud claim. Optional, no audience check is performed when empty.
+	Audience []string
+
+	// Extra tolerance applied when checking a token's nbf claim against the current time, to
+	// absorb clock skew between the service that issued the token and the one validating it.
+	// Optional, defaults to zero.
+	NotBeforeSkew time.Duration
+
+	// Extra tolerance applied when checking a token's exp claim against the current time, for the
+	// same reason as NotBeforeSkew. It is also added to NotBeforeSkew when checking nbf. Optional,
+	// defaults to zero.
+	Leeway time.Duration
+
+	// Claims key under which the identity is stored and looked up by the default IdentityHandler.
+	// Optional, defaults to "id".
+	IdentityKey string
+
+	// Callback function used to turn the claims of a successfully validated token into the value
+	// stored in r.Env["REMOTE_USER"], e.g. hydrating a *User from a repository using
+	// claims[IdentityKey]. Optional; if nil, claims[IdentityKey] is stored as-is, which keeps the
+	// previous string-only behavior working unchanged.
+	IdentityHandler func(claims map[string]interface{}) interface{}
+
+	// Callback function that should perform the authentication of the user based on userId and
+	// password. Must return the authenticated identity and a nil error on success, or a nil value
+	// and an error on failure. The returned value is passed to PayloadFunc, or stored directly
+	// under claims[IdentityKey] if PayloadFunc is nil. Required.
+	//
+	// Callers upgrading from the previous `func(userId, password string) bool` signature can keep
+	// their function body unchanged and wrap it with AdaptLegacyAuthenticator.
+	Authenticator func(userId string, password string) (interface{}, error)
+
+	// Callback function that should perform the authorization of the identity extracted from the
+	// token by IdentityHandler. Called only after an authentication success. Must return true on
+	// success, false on failure. Optional, default to success.
+	Authorizator func(data interface{}, r *rest.Request) bool
 
 	// Callback function that will be called during login.
 	// Using this function it is possible to add additional payload data to the webtoken.
-	// The data is then made available during requests via request.Env["JWT_PAYLOAD"].
+	// data is the identity returned by Authenticator. The data is then made available during
+	// requests via request.Env["JWT_PAYLOAD"].
 	// Note that the payload is not encrypted.
 	// The attributes mentioned on jwt.io can't be used as keys for the map.
-	// Optional, by default no additional data will be set.
-	PayloadFunc func(userId string) map[string]interface{}
+	// Optional; if nil, claims[IdentityKey] is set to the Authenticator's return value.
+	//
+	// Callers upgrading from the previous `func(userId string) map[string]interface{}` signature
+	// can keep their function body unchanged and wrap it with AdaptLegacyPayloadFunc.
+	PayloadFunc func(data interface{}) map[string]interface{}
+
+	// Callback invoked whenever a request fails authentication or authorization, so apps can
+	// customize the error response body or log the failure centrally. code is 401, except for
+	// ErrForbidden (an authenticated identity rejected by Authorizator) which is 403. message is
+	// err.Error(); err is one of the sentinel errors declared in this package (ErrExpiredToken,
+	// ErrForbidden, ...) wherever parseToken/Authenticator/Authorizator produced one, so
+	// implementations can errors.Is(err, ...) and branch on the reason instead of matching against
+	// message. Optional; the default sets "WWW-Authenticate: Bearer realm=..." per RFC 6750 and
+	// writes message via rest.Error.
+	Unauthorized func(w rest.ResponseWriter, r *rest.Request, code int, message string, err error)
+
+	// Callback invoked by LoginHandler and RefreshHandler once a new token has been issued, so apps
+	// can customize the response body. Optional; the default replies {"token": "TOKEN"}.
+	LoginResponse func(w rest.ResponseWriter, r *rest.Request, token string, expire time.Time)
 }
 
 // MiddlewareFunc makes JWTMiddleware implement the Middleware interface.
@@ -64,7 +187,24 @@ func (mw *JWTMiddleware) MiddlewareFunc(handler rest.HandlerFunc) rest.HandlerFu
 	if mw.SigningAlgorithm == "" {
 		mw.SigningAlgorithm = "HS256"
 	}
-	if mw.Key == nil {
+	if mw.IdentityKey == "" {
+		mw.IdentityKey = "id"
+	}
+	if mw.TokenHeadName == "" {
+		mw.TokenHeadName = "Bearer"
+	}
+	if mw.CookieName == "" {
+		mw.CookieName = "jwt"
+	}
+	if mw.CookiePath == "" {
+		mw.CookiePath = "/"
+	}
+	mw.extractors = buildExtractors(mw.TokenLookup, mw.TokenHeadName)
+	if mw.usingPublicKeyAlgo() {
+		if err := mw.readKeys(); err != nil {
+			log.Fatal(err)
+		}
+	} else if mw.Key == nil {
 		log.Fatal("Key required")
 	}
 	if mw.Timeout == 0 {
@@ -74,35 +214,121 @@ func (mw *JWTMiddleware) MiddlewareFunc(handler rest.HandlerFunc) rest.HandlerFu
 		log.Fatal("Authenticator is required")
 	}
 	if mw.Authorizator == nil {
-		mw.Authorizator = func(userId string, r *rest.Request) bool {
+		mw.Authorizator = func(data interface{}, r *rest.Request) bool {
 			return true
 		}
 	}
+	if mw.Unauthorized == nil {
+		mw.Unauthorized = func(w rest.ResponseWriter, r *rest.Request, code int, message string, err error) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", mw.Realm))
+			rest.Error(w, message, code)
+		}
+	}
+	if mw.LoginResponse == nil {
+		mw.LoginResponse = func(w rest.ResponseWriter, r *rest.Request, token string, expire time.Time) {
+			w.WriteJson(resultToken{Token: token})
+		}
+	}
 
 	return func(w rest.ResponseWriter, r *rest.Request) { mw.middlewareImpl(w, r, handler) }
 }
 
+// usingPublicKeyAlgo reports whether SigningAlgorithm is an RSA or ECDSA algorithm, as opposed to HMAC.
+func (mw *JWTMiddleware) usingPublicKeyAlgo() bool {
+	switch mw.SigningAlgorithm {
+	case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512":
+		return true
+	}
+	return false
+}
+
+// readKeys loads PrivKey/PubKey from PrivKeyFile/PubKeyFile when those paths are set, and otherwise
+// requires PrivKey/PubKey to already be populated.
+func (mw *JWTMiddleware) readKeys() error {
+	if mw.PrivKeyFile != "" {
+		privBytes, err := ioutil.ReadFile(mw.PrivKeyFile)
+		if err != nil {
+			return errors.New("jwt: could not read private key file: " + err.Error())
+		}
+		if strings.HasPrefix(mw.SigningAlgorithm, "ES") {
+			mw.PrivKey, err = jwt.ParseECPrivateKeyFromPEM(privBytes)
+		} else {
+			mw.PrivKey, err = jwt.ParseRSAPrivateKeyFromPEM(privBytes)
+		}
+		if err != nil {
+			return errors.New("jwt: could not parse private key: " + err.Error())
+		}
+	}
+	if mw.PubKeyFile != "" {
+		pubBytes, err := ioutil.ReadFile(mw.PubKeyFile)
+		if err != nil {
+			return errors.New("jwt: could not read public key file: " + err.Error())
+		}
+		if strings.HasPrefix(mw.SigningAlgorithm, "ES") {
+			mw.PubKey, err = jwt.ParseECPublicKeyFromPEM(pubBytes)
+		} else {
+			mw.PubKey, err = jwt.ParseRSAPublicKeyFromPEM(pubBytes)
+		}
+		if err != nil {
+			return errors.New("jwt: could not parse public key: " + err.Error())
+		}
+	}
+	if mw.PrivKey == nil {
+		return errors.New("jwt: PrivKey or PrivKeyFile required for " + mw.SigningAlgorithm)
+	}
+	if mw.PubKey == nil {
+		return errors.New("jwt: PubKey or PubKeyFile required for " + mw.SigningAlgorithm)
+	}
+	return nil
+}
+
+// signingKey returns the key that should be passed to Token.SignedString for the configured
+// SigningAlgorithm.
+func (mw *JWTMiddleware) signingKey() interface{} {
+	if mw.usingPublicKeyAlgo() {
+		return mw.PrivKey
+	}
+	return mw.Key
+}
+
+// verifyKey returns the key that should be used to verify an incoming token's signature for the
+// configured SigningAlgorithm.
+func (mw *JWTMiddleware) verifyKey() interface{} {
+	if mw.usingPublicKeyAlgo() {
+		return mw.PubKey
+	}
+	return mw.Key
+}
+
 func (mw *JWTMiddleware) middlewareImpl(w rest.ResponseWriter, r *rest.Request, handler rest.HandlerFunc) {
 	token, err := mw.parseToken(r)
 
 	if err != nil {
-		mw.unauthorized(w, err.Error())
+		mw.unauthorized(w, r, err)
 		return
 	}
 
-	id := token.Claims["id"].(string)
+	identity := mw.identityFromClaims(token.Claims)
 
-	r.Env["REMOTE_USER"] = id
+	r.Env["REMOTE_USER"] = identity
 	r.Env["JWT_PAYLOAD"] = token.Claims
 
-	if !mw.Authorizator(id, r) {
-		mw.unauthorized(w, "Permission Denied")
+	if !mw.Authorizator(identity, r) {
+		mw.unauthorized(w, r, ErrForbidden)
 		return
 	}
 
 	handler(w, r)
 }
 
+// identityFromClaims applies IdentityHandler to claims, falling back to claims[IdentityKey].
+func (mw *JWTMiddleware) identityFromClaims(claims map[string]interface{}) interface{} {
+	if mw.IdentityHandler != nil {
+		return mw.IdentityHandler(claims)
+	}
+	return claims[mw.IdentityKey]
+}
+
 // ExtractClaims allows to retrieve the payload
 func ExtractClaims(r *rest.Request) map[string]interface{} {
 	if r.Env["JWT_PAYLOAD"] == nil {
@@ -113,6 +339,12 @@ func ExtractClaims(r *rest.Request) map[string]interface{} {
 	return jwtClaims
 }
 
+// ExtractIdentity returns the value the middleware stored in r.Env["REMOTE_USER"]: the result of
+// IdentityHandler if one is configured, otherwise claims[IdentityKey].
+func ExtractIdentity(r *rest.Request) interface{} {
+	return r.Env["REMOTE_USER"]
+}
+
 type resultToken struct {
 	Token string `json:"token"`
 }
@@ -130,60 +362,111 @@ func (mw *JWTMiddleware) LoginHandler(w rest.ResponseWriter, r *rest.Request) {
 	err := r.DecodeJsonPayload(&loginVals)
 
 	if err != nil {
-		mw.unauthorized(w, "Error Reading Login Values")
+		mw.unauthorized(w, r, errors.New("jwt: error reading login values"))
 		return
 	}
 
-	if !mw.Authenticator(loginVals.Username, loginVals.Password) {
-		mw.unauthorized(w, "Not Authenticated")
+	data, err := mw.Authenticator(loginVals.Username, loginVals.Password)
+	if err != nil {
+		mw.unauthorized(w, r, ErrFailedAuthentication)
 		return
 	}
 
 	token := jwt.New(jwt.GetSigningMethod(mw.SigningAlgorithm))
 
 	if mw.PayloadFunc != nil {
-		for key, value := range mw.PayloadFunc(loginVals.Username) {
+		for key, value := range mw.PayloadFunc(data) {
 			token.Claims[key] = value
 		}
+	} else {
+		token.Claims[mw.IdentityKey] = data
 	}
 
-	token.Claims["id"] = loginVals.Username
-	token.Claims["exp"] = time.Now().Add(mw.Timeout).Unix()
+	now := time.Now()
+	expire := now.Add(mw.Timeout)
+	token.Claims["exp"] = expire.Unix()
+	token.Claims["iat"] = now.Unix()
+	token.Claims["nbf"] = now.Unix()
+	if mw.Issuer != "" {
+		token.Claims["iss"] = mw.Issuer
+	}
+	if len(mw.Audience) > 0 {
+		token.Claims["aud"] = mw.Audience
+	}
 	if mw.MaxRefresh != 0 {
-		token.Claims["orig_iat"] = time.Now().Unix()
+		token.Claims["orig_iat"] = now.Unix()
 	}
-	tokenString, err := token.SignedString(mw.Key)
-
+	jti, err := newJTI()
 	if err != nil {
-		mw.unauthorized(w, "Error creating token")
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
 		return
 	}
-	type responseStruct struct {
-		Token string `json:"token"`
-		ID    interface{}
-	}
+	token.Claims["jti"] = jti
+	tokenString, err := token.SignedString(mw.signingKey())
 
-	w.WriteJson(responseStruct{Token: tokenString, ID: token.Claims["userid"]})
+	if err != nil {
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
+		return
+	}
+	mw.setCookie(w, tokenString, expire)
+	mw.LoginResponse(w, r, tokenString, expire)
 }
 
 func (mw *JWTMiddleware) parseToken(r *rest.Request) (*jwt.Token, error) {
-	authHeader := r.Header.Get("Authorization")
+	tokenString, err := mw.extractToken(r)
+	if err != nil {
+		return nil, err
+	}
 
-	if authHeader == "" {
-		return nil, errors.New("Auth header empty")
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Reject "none" outright rather than relying on it simply failing to match
+		// SigningAlgorithm below, so the classic alg:none attack is refused explicitly regardless
+		// of how the rest of this check evolves.
+		if token.Method == jwt.SigningMethodNone {
+			return nil, ErrInvalidSigningAlgorithm
+		}
+		for _, disabled := range mw.DisabledAlgorithms {
+			if token.Method.Alg() == disabled {
+				return nil, ErrInvalidSigningAlgorithm
+			}
+		}
+		// Comparing the token's method against the configured SigningAlgorithm (rather than just
+		// its family) prevents an attacker from presenting an HS* token signed with the public key
+		// as if it were a trusted RS*/ES* token, or vice versa.
+		if jwt.GetSigningMethod(mw.SigningAlgorithm) != token.Method {
+			return nil, ErrInvalidSigningAlgorithm
+		}
+		return mw.verifyKey(), nil
+	})
+	// jwt.Parse enforces exp/nbf itself with zero tolerance; when that is the only failure, fall
+	// through to validateTiming below so Leeway/NotBeforeSkew get a say. Any other failure (bad
+	// signature, malformed token, ...) is returned as-is.
+	if err != nil && !(token != nil && onlyTimingFailed(err)) {
+		return nil, translateParseError(err)
 	}
 
-	parts := strings.SplitN(authHeader, " ", 2)
-	if !(len(parts) == 2 && parts[0] == "Bearer") {
-		return nil, errors.New("Invalid auth header")
+	if err := mw.validateTiming(token.Claims); err != nil {
+		return nil, err
+	}
+	if err := mw.validateIssuerAudience(token.Claims); err != nil {
+		return nil, err
 	}
 
-	return jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod(mw.SigningAlgorithm) != token.Method {
-			return nil, errors.New("Invalid signing algorithm")
+	if mw.TokenStore != nil {
+		jti, _ := token.Claims["jti"].(string)
+		if jti == "" {
+			return nil, ErrMissingJTI
 		}
-		return mw.Key, nil
-	})
+		revoked, err := mw.TokenStore.IsRevoked(jti)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return token, nil
 }
 
 // RefreshHandler can be used to refresh a token. The token still needs to be valid on refresh.
@@ -194,14 +477,18 @@ func (mw *JWTMiddleware) RefreshHandler(w rest.ResponseWriter, r *rest.Request)
 
 	// Token should be valid anyway as the RefreshHandler is authed
 	if err != nil {
-		mw.unauthorized(w, err.Error())
+		mw.unauthorized(w, r, err)
 		return
 	}
 
-	origIat := int64(token.Claims["orig_iat"].(float64))
+	origIat, err := claimUnixTime(token.Claims["orig_iat"])
+	if err != nil {
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
+		return
+	}
 
 	if origIat < time.Now().Add(-mw.MaxRefresh).Unix() {
-		mw.unauthorized(w, "Error Creating Token")
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
 		return
 	}
 
@@ -211,20 +498,156 @@ func (mw *JWTMiddleware) RefreshHandler(w rest.ResponseWriter, r *rest.Request)
 		newToken.Claims[key] = token.Claims[key]
 	}
 
-	newToken.Claims["id"] = token.Claims["id"]
-	newToken.Claims["exp"] = time.Now().Add(mw.Timeout).Unix()
+	newToken.Claims[mw.IdentityKey] = token.Claims[mw.IdentityKey]
+	now := time.Now()
+	expire := now.Add(mw.Timeout)
+	newToken.Claims["exp"] = expire.Unix()
+	newToken.Claims["iat"] = now.Unix()
+	newToken.Claims["nbf"] = now.Unix()
 	newToken.Claims["orig_iat"] = origIat
-	tokenString, err := newToken.SignedString(mw.Key)
+	jti, err := newJTI()
+	if err != nil {
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
+		return
+	}
+	newToken.Claims["jti"] = jti
+	tokenString, err := newToken.SignedString(mw.signingKey())
+
+	if err != nil {
+		mw.unauthorized(w, r, ErrFailedTokenCreation)
+		return
+	}
+
+	mw.setCookie(w, tokenString, expire)
+	mw.LoginResponse(w, r, tokenString, expire)
+}
+
+// setCookie sets the issued token as a cookie named CookieName when SendCookie is enabled, for
+// clients that prefer not to manage the Authorization header themselves (e.g. browser SPAs).
+func (mw *JWTMiddleware) setCookie(w rest.ResponseWriter, tokenString string, expire time.Time) {
+	if !mw.SendCookie {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     mw.CookieName,
+		Value:    tokenString,
+		Path:     mw.CookiePath,
+		Domain:   mw.CookieDomain,
+		Expires:  expire,
+		Secure:   mw.SecureCookie,
+		HttpOnly: mw.CookieHTTPOnly,
+		SameSite: mw.CookieSameSite,
+	})
+}
 
+// LogoutHandler revokes the token used to authenticate the current request until its natural
+// expiry, so it can no longer be used to authenticate subsequent requests. Shall be put under an
+// endpoint that is using the JWTMiddleware. Requires TokenStore to be configured.
+func (mw *JWTMiddleware) LogoutHandler(w rest.ResponseWriter, r *rest.Request) {
+	if mw.TokenStore == nil {
+		rest.Error(w, "Logout requires a TokenStore", http.StatusNotImplemented)
+		return
+	}
+
+	token, err := mw.parseToken(r)
+	if err != nil {
+		mw.unauthorized(w, r, err)
+		return
+	}
+
+	jti, _ := token.Claims["jti"].(string)
+	if jti == "" {
+		mw.unauthorized(w, r, ErrMissingJTI)
+		return
+	}
+
+	expUnix, err := claimUnixTime(token.Claims["exp"])
+	if err != nil {
+		mw.unauthorized(w, r, ErrMissingExpClaim)
+		return
+	}
+	exp := time.Unix(expUnix, 0)
+	if err := mw.TokenStore.Revoke(jti, exp); err != nil {
+		rest.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteJson(map[string]string{"status": "logged out"})
+}
+
+// unauthorized reports err to the client via the configured Unauthorized callback, as a 401
+// except for ErrForbidden (an authenticated identity rejected by Authorizator), which is a 403.
+func (mw *JWTMiddleware) unauthorized(w rest.ResponseWriter, r *rest.Request, err error) {
+	mw.Unauthorized(w, r, statusForError(err), err.Error(), err)
+}
+
+// statusForError picks the HTTP status that matches a sentinel error's semantics.
+func statusForError(err error) int {
+	if err == ErrForbidden {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// jwk is a single entry of a JSON Web Key Set, as described by RFC 7517.
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKSHandler publishes the configured public key as a JSON Web Key Set, so that other services can
+// verify tokens issued by this middleware without being given the private key or HMAC secret. Only
+// meaningful when SigningAlgorithm is an RS* or ES* algorithm; it is typically exposed at
+// /.well-known/jwks.json.
+func (mw *JWTMiddleware) JWKSHandler(w rest.ResponseWriter, r *rest.Request) {
+	if !mw.usingPublicKeyAlgo() {
+		rest.Error(w, "JWKS is only available for RSA/ECDSA signing algorithms", http.StatusNotFound)
+		return
+	}
+
+	key, err := mw.publicJWK()
 	if err != nil {
-		mw.unauthorized(w, "Error Creating Token")
+		rest.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteJson(resultToken{Token: tokenString})
+	w.WriteJson(jwks{Keys: []jwk{key}})
 }
 
-func (mw *JWTMiddleware) unauthorized(w rest.ResponseWriter, status string) {
-	w.Header().Set("WWW-Authenticate", "JWT realm="+mw.Realm)
-	rest.Error(w, status, http.StatusUnauthorized)
+func (mw *JWTMiddleware) publicJWK() (jwk, error) {
+	switch pub := mw.PubKey.(type) {
+	case *rsa.PublicKey:
+		return jwk{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: mw.SigningAlgorithm,
+			Kid: mw.SigningAlgorithm,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return jwk{
+			Kty: "EC",
+			Use: "sig",
+			Alg: mw.SigningAlgorithm,
+			Kid: mw.SigningAlgorithm,
+			Crv: pub.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}, nil
+	default:
+		return jwk{}, errors.New("jwt: unsupported public key type")
+	}
 }
@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// onlyTimingFailed reports whether a jwt.Parse error is solely due to the exp/nbf checks built into
+// jwt-go, as opposed to a bad signature or malformed token. It lets parseToken re-run those specific
+// checks itself with Leeway/NotBeforeSkew applied, instead of the library's zero-tolerance defaults.
+func onlyTimingFailed(err error) bool {
+	verr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return false
+	}
+	const timing = jwt.ValidationErrorExpired | jwt.ValidationErrorNotValidYet
+	return verr.Errors&^timing == 0
+}
+
+// translateParseError maps a jwt.Parse error back to one of this package's sentinels where
+// possible. jwt-go wraps whatever error the keyfunc returns in `&jwt.ValidationError{Inner: err,
+// Errors: ValidationErrorUnverifiable}` without giving ValidationError an Unwrap method, so a
+// sentinel like ErrInvalidSigningAlgorithm returned by the keyfunc in parseToken would otherwise
+// be lost and a custom Unauthorized callback could never errors.Is against it.
+func translateParseError(err error) error {
+	verr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return err
+	}
+	switch verr.Inner {
+	case ErrInvalidSigningAlgorithm:
+		return ErrInvalidSigningAlgorithm
+	}
+	return err
+}
+
+// validateTiming requires an exp claim (so a token can't be valid forever) and checks it, along
+// with nbf when present, against the current time, applying Leeway and NotBeforeSkew as
+// tolerances for clock drift between services.
+func (mw *JWTMiddleware) validateTiming(claims map[string]interface{}) error {
+	now := time.Now()
+
+	raw, ok := claims["exp"]
+	if !ok {
+		return ErrMissingExpClaim
+	}
+	exp, err := claimUnixTime(raw)
+	if err != nil {
+		return ErrExpiredToken
+	}
+	if now.After(time.Unix(exp, 0).Add(mw.Leeway)) {
+		return ErrExpiredToken
+	}
+
+	if raw, ok := claims["nbf"]; ok {
+		nbf, err := claimUnixTime(raw)
+		if err != nil {
+			return ErrTokenNotValidYet
+		}
+		if now.Add(mw.Leeway + mw.NotBeforeSkew).Before(time.Unix(nbf, 0)) {
+			return ErrTokenNotValidYet
+		}
+	}
+
+	return nil
+}
+
+// validateIssuerAudience checks the iss and aud claims against Issuer and Audience, when those are
+// configured. A token is accepted if its aud claim contains at least one configured audience.
+func (mw *JWTMiddleware) validateIssuerAudience(claims map[string]interface{}) error {
+	if mw.Issuer != "" {
+		iss, _ := claims["iss"].(string)
+		if iss != mw.Issuer {
+			return ErrInvalidIssuer
+		}
+	}
+
+	if len(mw.Audience) > 0 && !audienceAllowed(claims["aud"], mw.Audience) {
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+// claimUnixTime converts a decoded exp/nbf claim (a JSON number, so a float64 once round-tripped
+// through encoding/json) into a Unix timestamp.
+func claimUnixTime(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case float64:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	default:
+		return 0, errors.New("not a timestamp")
+	}
+}
+
+// audienceAllowed reports whether an aud claim (a bare string or an array of strings, per RFC 7519)
+// contains any of the allowed audiences.
+func audienceAllowed(claim interface{}, allowed []string) bool {
+	var tokenAudiences []string
+	switch v := claim.(type) {
+	case string:
+		tokenAudiences = []string{v}
+	case []interface{}:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok {
+				tokenAudiences = append(tokenAudiences, s)
+			}
+		}
+	}
+
+	for _, want := range allowed {
+		for _, got := range tokenAudiences {
+			if want == got {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package jwt
+
+import "errors"
+
+// Sentinel errors returned by parseToken (and, by extension, MiddlewareFunc, RefreshHandler and
+// LogoutHandler) so that a custom Unauthorized callback, or application code further up the stack,
+// can distinguish failure reasons instead of pattern-matching response strings.
+var (
+	// ErrMissingAuthHeader is returned when none of the configured TokenLookup sources yielded a
+	// token.
+	ErrMissingAuthHeader = errors.New("jwt: auth header is empty")
+
+	// ErrInvalidAuthHeader is returned when a header source was present but malformed, e.g. missing
+	// the configured TokenHeadName scheme.
+	ErrInvalidAuthHeader = errors.New("jwt: invalid auth header")
+
+	// ErrInvalidSigningAlgorithm is returned when a token's alg does not match the configured
+	// SigningAlgorithm, is explicitly disabled via DisabledAlgorithms, or is "none".
+	ErrInvalidSigningAlgorithm = errors.New("jwt: invalid signing algorithm")
+
+	// ErrExpiredToken is returned when a token's exp claim, plus Leeway, is in the past.
+	ErrExpiredToken = errors.New("jwt: token expired")
+
+	// ErrMissingExpClaim is returned when a token carries no exp claim at all, so it would
+	// otherwise never expire.
+	ErrMissingExpClaim = errors.New("jwt: token missing exp claim")
+
+	// ErrTokenNotValidYet is returned when a token's nbf claim, minus NotBeforeSkew and Leeway, is
+	// in the future.
+	ErrTokenNotValidYet = errors.New("jwt: token not valid yet")
+
+	// ErrInvalidIssuer is returned when Issuer is set and a token's iss claim does not match it.
+	ErrInvalidIssuer = errors.New("jwt: invalid issuer")
+
+	// ErrInvalidAudience is returned when Audience is set and a token's aud claim contains none of
+	// the configured values.
+	ErrInvalidAudience = errors.New("jwt: invalid audience")
+
+	// ErrMissingJTI is returned when TokenStore is configured and a token has no jti claim to check
+	// or revoke.
+	ErrMissingJTI = errors.New("jwt: token missing jti claim")
+
+	// ErrTokenRevoked is returned when TokenStore reports a token's jti as revoked.
+	ErrTokenRevoked = errors.New("jwt: token has been revoked")
+
+	// ErrFailedAuthentication is returned by LoginHandler when Authenticator rejects the supplied
+	// credentials.
+	ErrFailedAuthentication = errors.New("jwt: failed authentication")
+
+	// ErrForbidden is returned when Authorizator rejects an otherwise valid, authenticated identity.
+	ErrForbidden = errors.New("jwt: permission denied")
+
+	// ErrFailedTokenCreation is returned by LoginHandler/RefreshHandler when a new token could not
+	// be assembled or signed.
+	ErrFailedTokenCreation = errors.New("jwt: failed to create token")
+)
@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/ant0ine/go-json-rest/rest"
+)
+
+// tokenExtractor pulls a raw token string out of an incoming request, returning an error when its
+// configured source had nothing to offer.
+type tokenExtractor func(r *rest.Request) (string, error)
+
+// defaultTokenLookup preserves the previous hardcoded behavior of reading Authorization: Bearer ...
+const defaultTokenLookup = "header:Authorization"
+
+// buildExtractors parses a TokenLookup string such as "header:Authorization,cookie:jwt,query:token"
+// into an ordered list of extractors, tried in turn by extractToken until one yields a token.
+// Unrecognized or malformed entries (no "kind:name" colon, or an unknown kind) are skipped; if that
+// leaves no usable entries at all, it's treated as misconfiguration and fails fast with log.Fatal,
+// like the other config checks in MiddlewareFunc, rather than silently 401ing every request.
+func buildExtractors(lookup, headName string) []tokenExtractor {
+	if lookup == "" {
+		lookup = defaultTokenLookup
+	}
+
+	var extractors []tokenExtractor
+	for _, source := range strings.Split(lookup, ",") {
+		parts := strings.SplitN(strings.TrimSpace(source), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		kind, name := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch kind {
+		case "header":
+			extractors = append(extractors, headerExtractor(name, headName))
+		case "cookie":
+			extractors = append(extractors, cookieExtractor(name))
+		case "query":
+			extractors = append(extractors, queryExtractor(name))
+		}
+	}
+	if len(extractors) == 0 {
+		log.Fatal("jwt: TokenLookup did not yield any usable \"source:name\" entries: " + lookup)
+	}
+	return extractors
+}
+
+func headerExtractor(header, headName string) tokenExtractor {
+	return func(r *rest.Request) (string, error) {
+		value := r.Header.Get(header)
+		if value == "" {
+			return "", ErrMissingAuthHeader
+		}
+		if headName == "" {
+			return value, nil
+		}
+		parts := strings.SplitN(value, " ", 2)
+		if !(len(parts) == 2 && parts[0] == headName) {
+			return "", ErrInvalidAuthHeader
+		}
+		return parts[1], nil
+	}
+}
+
+func cookieExtractor(name string) tokenExtractor {
+	return func(r *rest.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", errors.New("jwt: cookie " + name + " is empty")
+		}
+		return cookie.Value, nil
+	}
+}
+
+func queryExtractor(name string) tokenExtractor {
+	return func(r *rest.Request) (string, error) {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			return "", errors.New("jwt: query parameter " + name + " is empty")
+		}
+		return value, nil
+	}
+}
+
+// extractToken tries mw.extractors in order and returns the first token found. If none yields a
+// token it returns ErrInvalidAuthHeader when a header source was present but malformed, and
+// ErrMissingAuthHeader otherwise.
+func (mw *JWTMiddleware) extractToken(r *rest.Request) (string, error) {
+	err := ErrMissingAuthHeader
+	for _, extract := range mw.extractors {
+		token, extractErr := extract(r)
+		if extractErr == nil && token != "" {
+			return token, nil
+		}
+		if extractErr == ErrInvalidAuthHeader {
+			err = ErrInvalidAuthHeader
+		}
+	}
+	return "", err
+}
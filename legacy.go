@@ -0,0 +1,41 @@
+package jwt
+
+// AdaptLegacyAuthenticator wraps an Authenticator using the pre-chunk0-2 signature
+// (func(userId, password string) bool) into the current
+// func(userId, password string) (interface{}, error) signature, so existing callers can keep
+// their authentication logic unchanged and switch over with:
+//
+//	Authenticator: jwt.AdaptLegacyAuthenticator(oldAuthenticator)
+//
+// The userId is returned as the identity on success, matching the previous behavior of storing
+// the username string in r.Env["REMOTE_USER"].
+func AdaptLegacyAuthenticator(fn func(userId, password string) bool) func(userId, password string) (interface{}, error) {
+	return func(userId, password string) (interface{}, error) {
+		if fn(userId, password) {
+			return userId, nil
+		}
+		return nil, ErrFailedAuthentication
+	}
+}
+
+// AdaptLegacyPayloadFunc wraps a PayloadFunc using the pre-chunk0-2 signature
+// (func(userId string) map[string]interface{}) into the current
+// func(data interface{}) map[string]interface{} signature, for use together with
+// AdaptLegacyAuthenticator:
+//
+//	PayloadFunc: jwt.AdaptLegacyPayloadFunc(oldPayloadFunc)
+//
+// data is expected to be the string identity produced by AdaptLegacyAuthenticator; it is ignored
+// if it isn't a string. As the previous LoginHandler did, the returned claims always carry the
+// username under "id", so r.Env["REMOTE_USER"] still holds it with the default IdentityKey.
+func AdaptLegacyPayloadFunc(fn func(userId string) map[string]interface{}) func(data interface{}) map[string]interface{} {
+	return func(data interface{}) map[string]interface{} {
+		userId, _ := data.(string)
+		claims := fn(userId)
+		if claims == nil {
+			claims = make(map[string]interface{})
+		}
+		claims["id"] = userId
+		return claims
+	}
+}
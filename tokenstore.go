@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenStore tracks revoked tokens so that logout and compromised-token scenarios can be enforced
+// even though JWTs are otherwise stateless. parseToken consults the store on every request when one
+// is configured, so implementations should be fast. A Redis-backed store is a natural fit for
+// multi-instance deployments: IsRevoked maps to EXISTS and Revoke to SETEX on a key such as
+// "jwt:revoked:<jti>" with a TTL equal to exp, letting Redis itself evict expired entries.
+type TokenStore interface {
+	// IsRevoked reports whether the token identified by jti has been revoked.
+	IsRevoked(jti string) (bool, error)
+
+	// Revoke marks the token identified by jti as revoked. exp is the token's own expiry; stores
+	// may use it to evict the entry once the token would have expired naturally anyway.
+	Revoke(jti string, exp time.Time) error
+}
+
+// MemoryTokenStore is an in-memory TokenStore suitable for single-instance deployments and tests.
+// Entries are evicted once their exp has passed. The zero value is not usable; create one with
+// NewMemoryTokenStore.
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryTokenStore creates an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{revoked: make(map[string]time.Time)}
+}
+
+// IsRevoked implements TokenStore.
+func (s *MemoryTokenStore) IsRevoked(jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictLocked()
+	_, revoked := s.revoked[jti]
+	return revoked, nil
+}
+
+// Revoke implements TokenStore.
+func (s *MemoryTokenStore) Revoke(jti string, exp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = exp
+	s.evictLocked()
+	return nil
+}
+
+// evictLocked drops entries whose exp has already passed. Callers must hold s.mu.
+func (s *MemoryTokenStore) evictLocked() {
+	now := time.Now()
+	for jti, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, jti)
+		}
+	}
+}
+
+// newJTI generates a random RFC 4122 version 4 UUID to use as a token's jti claim.
+func newJTI() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}